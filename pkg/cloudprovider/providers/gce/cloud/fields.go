@@ -0,0 +1,155 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// fieldMismatchError reports fields that exist on the source struct but
+// have no counterpart on the destination struct, and so were dropped by
+// copyFields. Callers typically log this to track field drift between the
+// generated GA/alpha/beta compute struct variants.
+type fieldMismatchError struct {
+	dst, src string
+	fields   []string
+}
+
+func (e *fieldMismatchError) Error() string {
+	return fmt.Sprintf("copyFields(%s, %s): fields %v present on source but not on destination", e.dst, e.src, e.fields)
+}
+
+// copyFields copies the fields of src into dst by walking both structs
+// with reflection and matching fields by JSON tag, falling back to the Go
+// field name when a field has no JSON tag. Matched fields are copied
+// directly if assignable or convertible, and structs and slices are
+// copied recursively. dst must be a non-nil pointer to a struct; src may
+// be a struct or a pointer to one.
+//
+// If src has fields with no match on dst, copyFields still copies
+// everything it can match and returns a *fieldMismatchError listing the
+// dropped fields, rather than failing outright -- this is expected when
+// converting from, say, an alpha struct to its GA counterpart.
+func copyFields(dst, src interface{}) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return fmt.Errorf("copyFields: dst must be a non-nil pointer, got %T", dst)
+	}
+	sv := reflect.ValueOf(src)
+	for sv.Kind() == reflect.Ptr {
+		if sv.IsNil() {
+			return nil
+		}
+		sv = sv.Elem()
+	}
+	if dv.Elem().Kind() != reflect.Struct || sv.Kind() != reflect.Struct {
+		return fmt.Errorf("copyFields: dst and src must both be structs, got %T and %T", dst, src)
+	}
+
+	var dropped []string
+	if err := copyStructFields(dv.Elem(), sv, &dropped); err != nil {
+		return err
+	}
+	if len(dropped) > 0 {
+		return &fieldMismatchError{dst: fmt.Sprintf("%T", dst), src: fmt.Sprintf("%T", src), fields: dropped}
+	}
+	return nil
+}
+
+func copyStructFields(dst, src reflect.Value, dropped *[]string) error {
+	st := src.Type()
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if sf.PkgPath != "" {
+			continue // unexported
+		}
+		name := jsonFieldName(sf)
+		di, ok := findField(dst.Type(), name)
+		if !ok {
+			*dropped = append(*dropped, name)
+			continue
+		}
+		if err := copyValue(dst.Field(di), src.Field(i), dropped); err != nil {
+			return fmt.Errorf("field %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func copyValue(dst, src reflect.Value, dropped *[]string) error {
+	switch {
+	case src.Type().AssignableTo(dst.Type()):
+		dst.Set(src)
+		return nil
+	case src.Kind() == reflect.Ptr:
+		if src.IsNil() {
+			return nil
+		}
+		if dst.Kind() == reflect.Ptr {
+			if dst.IsNil() {
+				dst.Set(reflect.New(dst.Type().Elem()))
+			}
+			return copyValue(dst.Elem(), src.Elem(), dropped)
+		}
+		return copyValue(dst, src.Elem(), dropped)
+	case src.Kind() == reflect.Struct && dst.Kind() == reflect.Struct:
+		return copyStructFields(dst, src, dropped)
+	case src.Kind() == reflect.Slice && dst.Kind() == reflect.Slice:
+		out := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			if err := copyValue(out.Index(i), src.Index(i), dropped); err != nil {
+				return err
+			}
+		}
+		dst.Set(out)
+		return nil
+	case src.Type().ConvertibleTo(dst.Type()):
+		dst.Set(src.Convert(dst.Type()))
+		return nil
+	default:
+		return fmt.Errorf("cannot copy %s to %s", src.Type(), dst.Type())
+	}
+}
+
+// jsonFieldName returns the name f is addressed by in JSON: its "json"
+// tag name, or its Go field name if the tag is absent, empty or "-".
+func jsonFieldName(f reflect.StructField) string {
+	if tag, ok := f.Tag.Lookup("json"); ok {
+		if i := strings.Index(tag, ","); i >= 0 {
+			tag = tag[:i]
+		}
+		if tag != "" && tag != "-" {
+			return tag
+		}
+	}
+	return f.Name
+}
+
+func findField(t reflect.Type, name string) (int, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if jsonFieldName(f) == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCopyFields(t *testing.T) {
+	t.Parallel()
+
+	var b B
+	if err := copyFields(&b, &A{"aa", "bb", "cc"}); err == nil {
+		t.Error("copyFields(&b, &A{...}) = nil, want *fieldMismatchError for dropped field C")
+	}
+	if want := (B{"aa", "bb", ""}); b != want {
+		t.Errorf("b == %+v, want %+v", b, want)
+	}
+}
+
+// backendGA and backendAlpha approximate the shape of the generated
+// compute.BackendService GA/alpha struct pair: mostly-aligned JSON tags,
+// a nested struct and a slice of structs, plus an alpha-only field that
+// has no GA counterpart.
+type backendGA struct {
+	Name     string         `json:"name"`
+	SelfLink string         `json:"selfLink"`
+	Backends []backendGroup `json:"backends"`
+	Timeout  backendTimeout `json:"timeoutSec"`
+}
+
+type backendAlpha struct {
+	Name       string         `json:"name"`
+	SelfLink   string         `json:"selfLink"`
+	Backends   []backendGroup `json:"backends"`
+	Timeout    backendTimeout `json:"timeoutSec"`
+	LocalityLB string         `json:"localityLbPolicy"`
+}
+
+type backendGroup struct {
+	Group          string  `json:"group"`
+	BalancingMode  string  `json:"balancingMode"`
+	MaxUtilization float64 `json:"maxUtilization"`
+}
+
+type backendTimeout struct {
+	Seconds int64 `json:"seconds"`
+}
+
+func newBackendAlpha() *backendAlpha {
+	return &backendAlpha{
+		Name:     "my-backend-service",
+		SelfLink: "https://www.googleapis.com/compute/alpha/projects/p/global/backendServices/my-backend-service",
+		Backends: []backendGroup{
+			{Group: "ig-1", BalancingMode: "UTILIZATION", MaxUtilization: 0.8},
+			{Group: "ig-2", BalancingMode: "RATE", MaxUtilization: 0.5},
+		},
+		Timeout:    backendTimeout{Seconds: 30},
+		LocalityLB: "ROUND_ROBIN",
+	}
+}
+
+func TestCopyFieldsNestedAndSlice(t *testing.T) {
+	t.Parallel()
+
+	src := newBackendAlpha()
+	var dst backendGA
+	err := copyFields(&dst, src)
+	if _, ok := err.(*fieldMismatchError); !ok {
+		t.Fatalf("copyFields(&dst, src) = %v, want *fieldMismatchError for dropped field localityLbPolicy", err)
+	}
+
+	want := backendGA{
+		Name:     src.Name,
+		SelfLink: src.SelfLink,
+		Backends: src.Backends,
+		Timeout:  src.Timeout,
+	}
+	if dst.Name != want.Name || dst.SelfLink != want.SelfLink || dst.Timeout != want.Timeout {
+		t.Errorf("dst == %+v, want %+v", dst, want)
+	}
+	if len(dst.Backends) != len(want.Backends) {
+		t.Fatalf("len(dst.Backends) = %d, want %d", len(dst.Backends), len(want.Backends))
+	}
+	for i := range dst.Backends {
+		if dst.Backends[i] != want.Backends[i] {
+			t.Errorf("dst.Backends[%d] == %+v, want %+v", i, dst.Backends[i], want.Backends[i])
+		}
+	}
+}
+
+func BenchmarkCopyFields(b *testing.B) {
+	src := newBackendAlpha()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var dst backendGA
+		copyFields(&dst, src)
+	}
+}
+
+// marshalViaJSON is the marshal-to-JSON-and-back copy that copyViaJSON
+// used before it was rewritten on top of copyFields; kept here only to
+// benchmark the reflective path against it.
+func marshalViaJSON(dst, src interface{}) error {
+	b, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
+
+func BenchmarkCopyViaJSON(b *testing.B) {
+	src := newBackendAlpha()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var dst backendGA
+		marshalViaJSON(&dst, src)
+	}
+}
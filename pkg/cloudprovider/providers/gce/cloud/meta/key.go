@@ -0,0 +1,82 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import "fmt"
+
+// KeyType specifies the scope of a Key: whether it identifies a global,
+// regional or zonal resource.
+type KeyType string
+
+const (
+	// Zonal keys identify resources that exist within a single zone.
+	Zonal KeyType = "zonal"
+	// Regional keys identify resources that exist within a single region.
+	Regional KeyType = "regional"
+	// Global keys identify resources that are not scoped to a zone or
+	// region.
+	Global KeyType = "global"
+)
+
+// Key is the identifier used to uniquely name a GCE resource within a
+// project. Exactly one of Zone or Region is set for zonal or regional
+// resources respectively; neither is set for global resources.
+type Key struct {
+	Name   string
+	Zone   string
+	Region string
+}
+
+// GlobalKey returns the Key for a global resource.
+func GlobalKey(name string) *Key {
+	return &Key{Name: name}
+}
+
+// RegionalKey returns the Key for a resource that exists in a region.
+func RegionalKey(name, region string) *Key {
+	return &Key{Name: name, Region: region}
+}
+
+// ZonalKey returns the Key for a resource that exists in a zone.
+func ZonalKey(name, zone string) *Key {
+	return &Key{Name: name, Zone: zone}
+}
+
+// Type returns whether k identifies a zonal, regional or global resource.
+func (k *Key) Type() KeyType {
+	switch {
+	case k.Zone != "":
+		return Zonal
+	case k.Region != "":
+		return Regional
+	default:
+		return Global
+	}
+}
+
+// String returns a human-readable representation of the key, suitable for
+// logging.
+func (k *Key) String() string {
+	switch k.Type() {
+	case Zonal:
+		return fmt.Sprintf("Key{%q, zone: %q}", k.Name, k.Zone)
+	case Regional:
+		return fmt.Sprintf("Key{%q, region: %q}", k.Name, k.Region)
+	default:
+		return fmt.Sprintf("Key{%q}", k.Name)
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package meta contains the API version and resource key types used to
+// address GCE compute resources across the GA, alpha and beta variants of
+// the API.
+package meta
+
+import "fmt"
+
+// Version is the API version to use for a GCE operation.
+type Version string
+
+const (
+	// VersionGA is the stable, generally available API.
+	VersionGA Version = "ga"
+	// VersionAlpha is the alpha API. Alpha features may change or be
+	// removed at any time and typically require allowlisting by Google.
+	VersionAlpha Version = "alpha"
+	// VersionBeta is the beta API.
+	VersionBeta Version = "beta"
+)
+
+// String returns the path segment used to address this version in a
+// resource URL, e.g. VersionGA is "v1" while VersionAlpha is "alpha".
+func (v Version) String() string {
+	if v == VersionGA {
+		return "v1"
+	}
+	return string(v)
+}
+
+// ParseVersion converts the version path segment of a resource URL (e.g.
+// "v1", "alpha", "beta") into a Version. It returns an error if s does not
+// name a known API version.
+func ParseVersion(s string) (Version, error) {
+	switch s {
+	case "v1":
+		return VersionGA, nil
+	case "alpha":
+		return VersionAlpha, nil
+	case "beta":
+		return VersionBeta, nil
+	}
+	return "", fmt.Errorf("invalid API version %q", s)
+}
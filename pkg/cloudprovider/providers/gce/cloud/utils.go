@@ -0,0 +1,250 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cloud contains helpers for building and parsing the resource
+// URLs used by the GCE compute API.
+package cloud
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/kubernetes/pkg/cloudprovider/providers/gce/cloud/meta"
+)
+
+// baseURL is the root of all GCE compute resource URLs, used whenever a
+// ResourceLinker or ResourceID doesn't pin down a different host.
+const baseURL = "https://www.googleapis.com/compute/"
+
+// validHosts are the hosts ResourceLinker.Parse recognizes in a full
+// self-link: the classic googleapis.com host, and the newer
+// compute.googleapis.com host used by regional/cross-project endpoints.
+var validHosts = []string{"www.googleapis.com", "compute.googleapis.com"}
+
+// ResourceID identifies a GCE resource, as parsed from either a full
+// self-link or the short "projects/{project}/..." form. Version records
+// the API surface (GA, alpha or beta) the URL addressed, so that a
+// ResourceID obtained from, say, an alpha self-link can be turned back
+// into a link against that same API version with SelfLink. Host records
+// which of validHosts the URL was addressed to, if it was a full self-link.
+type ResourceID struct {
+	ProjectID string
+	Resource  string
+	Key       *meta.Key
+	Version   meta.Version
+	Host      string
+}
+
+// Equal returns true if r and other identify the same resource through the
+// same API version. Host is deliberately excluded: www.googleapis.com and
+// compute.googleapis.com name the same underlying resource.
+func (r *ResourceID) Equal(other *ResourceID) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+	if r.ProjectID != other.ProjectID || r.Resource != other.Resource || r.Version != other.Version {
+		return false
+	}
+	if r.Key == nil || other.Key == nil {
+		return r.Key == other.Key
+	}
+	return *r.Key == *other.Key
+}
+
+// SelfLink returns the self-link URL for r, addressed through the host and
+// API version it was parsed with. An empty Host means www.googleapis.com
+// and an empty Version means VersionGA.
+func (r *ResourceID) SelfLink() string {
+	ver := r.Version
+	if ver == "" {
+		ver = meta.VersionGA
+	}
+	l := &ResourceLinker{DefaultProject: r.ProjectID}
+	if r.Host != "" {
+		l.BaseURL = fmt.Sprintf("https://%s/compute/", r.Host)
+	}
+	if r.Key == nil {
+		return fmt.Sprintf("%s%s/projects/%s", l.baseURL(), ver, r.ProjectID)
+	}
+	return l.SelfLink(ver, r.Resource, *r.Key)
+}
+
+// SelfLink returns the self-link URL, against the default
+// www.googleapis.com host, for the named resource in project, addressed
+// through the given API version.
+func SelfLink(ver meta.Version, project, resource string, key meta.Key) string {
+	return (&ResourceLinker{DefaultProject: project}).SelfLink(ver, resource, key)
+}
+
+// ParseResourceURL parses resource URLs of the following forms into a
+// ResourceID:
+//
+//   https://www.googleapis.com/compute/{ver}/projects/{project}/...
+//   https://compute.googleapis.com/compute/{ver}/projects/{project}/...
+//   projects/{project}/...
+//
+// where {ver} is one of "v1", "alpha" or "beta". The short
+// "projects/{project}/..." form has no version segment and is assumed to
+// be VersionGA. ParseResourceURL places no restriction on which project
+// the URL names; use a ResourceLinker with DefaultProject set to validate
+// against a specific project.
+func ParseResourceURL(url string) (*ResourceID, error) {
+	return (&ResourceLinker{}).Parse(url)
+}
+
+// ResourceLinker builds and parses GCE compute resource URLs for a
+// specific base URL and, optionally, a single expected project. Real
+// GCE-backed clusters routinely reference resources that live in a
+// different project than the one they were provisioned in -- shared-VPC
+// host projects, peered networks, cross-project service attachments -- so
+// a ResourceLinker with no DefaultProject can Parse a link naming any
+// project, while SelfLink's WithProject option builds a link into a
+// project other than DefaultProject without string concatenation.
+type ResourceLinker struct {
+	// BaseURL is the scheme, host and path prefix prepended to all
+	// resource URLs built by SelfLink, e.g.
+	// "https://compute.googleapis.com/compute/". Defaults to baseURL
+	// (the www.googleapis.com host) if empty. Parse recognizes either
+	// host regardless of BaseURL.
+	BaseURL string
+	// DefaultProject, if set, is the project used by SelfLink when no
+	// WithProject option is given, and the only project Parse will
+	// accept; a URL naming a different project is rejected.
+	DefaultProject string
+}
+
+func (l *ResourceLinker) baseURL() string {
+	if l.BaseURL == "" {
+		return baseURL
+	}
+	return l.BaseURL
+}
+
+// LinkOption customizes a single call to ResourceLinker.SelfLink.
+type LinkOption func(*linkOptions)
+
+type linkOptions struct {
+	project string
+}
+
+// WithProject overrides the project used by a single SelfLink call,
+// instead of the ResourceLinker's DefaultProject. This is how callers
+// build cross-project links, e.g. the self-link of a shared-VPC network
+// that lives in a host project different from the resource being linked.
+func WithProject(project string) LinkOption {
+	return func(o *linkOptions) { o.project = project }
+}
+
+// SelfLink returns the self-link URL for the named resource, addressed
+// through l.BaseURL and the given API version, in l.DefaultProject unless
+// overridden with WithProject.
+func (l *ResourceLinker) SelfLink(ver meta.Version, resource string, key meta.Key, opts ...LinkOption) string {
+	o := linkOptions{project: l.DefaultProject}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	u := fmt.Sprintf("%s%s/projects/%s", l.baseURL(), ver, o.project)
+	switch key.Type() {
+	case meta.Zonal:
+		return fmt.Sprintf("%s/zones/%s/%s/%s", u, key.Zone, resource, key.Name)
+	case meta.Regional:
+		return fmt.Sprintf("%s/regions/%s/%s/%s", u, key.Region, resource, key.Name)
+	default:
+		return fmt.Sprintf("%s/%s/%s", u, resource, key.Name)
+	}
+}
+
+// Parse parses a resource URL into a ResourceID, as described on
+// ParseResourceURL. If l.DefaultProject is set, Parse rejects a URL naming
+// any other project.
+func (l *ResourceLinker) Parse(url string) (*ResourceID, error) {
+	errNotValid := fmt.Errorf("%q is not a valid resource URL", url)
+
+	ver := meta.VersionGA
+	host := ""
+	for _, h := range validHosts {
+		prefix := fmt.Sprintf("https://%s/compute/", h)
+		if !strings.HasPrefix(url, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(url, prefix)
+		parts := strings.SplitN(rest, "/", 2)
+		if len(parts) != 2 {
+			return nil, errNotValid
+		}
+		v, err := meta.ParseVersion(parts[0])
+		if err != nil {
+			return nil, errNotValid
+		}
+		ver, host, url = v, h, parts[1]
+		break
+	}
+
+	parts := strings.Split(url, "/")
+	if len(parts) < 2 || parts[0] != "projects" {
+		return nil, errNotValid
+	}
+	project := parts[1]
+	if l.DefaultProject != "" && project != l.DefaultProject {
+		return nil, fmt.Errorf("resource URL %q names project %q, want %q", url, project, l.DefaultProject)
+	}
+	rid := &ResourceID{ProjectID: project, Version: ver, Host: host}
+	parts = parts[2:]
+
+	switch len(parts) {
+	case 0:
+		rid.Resource = "projects"
+		return rid, nil
+	case 2:
+		switch parts[0] {
+		case "regions", "zones":
+			rid.Resource = parts[0]
+			rid.Key = meta.GlobalKey(parts[1])
+			return rid, nil
+		}
+	case 3:
+		if parts[0] == "global" {
+			rid.Resource = parts[1]
+			rid.Key = meta.GlobalKey(parts[2])
+			return rid, nil
+		}
+	case 4:
+		switch parts[0] {
+		case "regions":
+			rid.Resource = parts[2]
+			rid.Key = meta.RegionalKey(parts[3], parts[1])
+			return rid, nil
+		case "zones":
+			rid.Resource = parts[2]
+			rid.Key = meta.ZonalKey(parts[3], parts[1])
+			return rid, nil
+		}
+	}
+	return nil, errNotValid
+}
+
+// copyViaJSON copies src to dest. It is retained for one release as a thin
+// wrapper around copyFields, which replaced its previous
+// marshal-to-JSON-and-back implementation; unlike copyFields, it does not
+// surface field drift between src and dest, since existing callers don't
+// inspect the error for that.
+func copyViaJSON(dest, src interface{}) error {
+	err := copyFields(dest, src)
+	if _, ok := err.(*fieldMismatchError); ok {
+		return nil
+	}
+	return err
+}
@@ -17,7 +17,6 @@ limitations under the License.
 package cloud
 
 import (
-	"errors"
 	"testing"
 
 	"k8s.io/kubernetes/pkg/cloudprovider/providers/gce/cloud/meta"
@@ -32,51 +31,55 @@ func TestParseResourceURL(t *testing.T) {
 	}{
 		{
 			"https://www.googleapis.com/compute/v1/projects/some-gce-project",
-			&ResourceID{"some-gce-project", "projects", nil},
+			&ResourceID{"some-gce-project", "projects", nil, meta.VersionGA, "www.googleapis.com"},
 		},
 		{
 			"https://www.googleapis.com/compute/v1/projects/some-gce-project/regions/us-central1",
-			&ResourceID{"some-gce-project", "regions", meta.GlobalKey("us-central1")},
+			&ResourceID{"some-gce-project", "regions", meta.GlobalKey("us-central1"), meta.VersionGA, "www.googleapis.com"},
 		},
 		{
 			"https://www.googleapis.com/compute/v1/projects/some-gce-project/zones/us-central1-b",
-			&ResourceID{"some-gce-project", "zones", meta.GlobalKey("us-central1-b")},
+			&ResourceID{"some-gce-project", "zones", meta.GlobalKey("us-central1-b"), meta.VersionGA, "www.googleapis.com"},
 		},
 		{
 			"https://www.googleapis.com/compute/v1/projects/some-gce-project/global/operations/operation-1513289952196-56054460af5a0-b1dae0c3-9bbf9dbf",
-			&ResourceID{"some-gce-project", "operations", meta.GlobalKey("operation-1513289952196-56054460af5a0-b1dae0c3-9bbf9dbf")},
+			&ResourceID{"some-gce-project", "operations", meta.GlobalKey("operation-1513289952196-56054460af5a0-b1dae0c3-9bbf9dbf"), meta.VersionGA, "www.googleapis.com"},
 		},
 		{
 			"https://www.googleapis.com/compute/alpha/projects/some-gce-project/regions/us-central1/addresses/my-address",
-			&ResourceID{"some-gce-project", "addresses", meta.RegionalKey("my-address", "us-central1")},
+			&ResourceID{"some-gce-project", "addresses", meta.RegionalKey("my-address", "us-central1"), meta.VersionAlpha, "www.googleapis.com"},
 		},
 		{
-			"https://www.googleapis.com/compute/v1/projects/some-gce-project/zones/us-central1-c/instances/instance-1",
-			&ResourceID{"some-gce-project", "instances", meta.ZonalKey("instance-1", "us-central1-c")},
+			"https://www.googleapis.com/compute/beta/projects/some-gce-project/zones/us-central1-c/instances/instance-1",
+			&ResourceID{"some-gce-project", "instances", meta.ZonalKey("instance-1", "us-central1-c"), meta.VersionBeta, "www.googleapis.com"},
+		},
+		{
+			"https://compute.googleapis.com/compute/v1/projects/some-gce-project/regions/us-central1/addresses/my-other-address",
+			&ResourceID{"some-gce-project", "addresses", meta.RegionalKey("my-other-address", "us-central1"), meta.VersionGA, "compute.googleapis.com"},
 		},
 		{
 			"projects/some-gce-project",
-			&ResourceID{"some-gce-project", "projects", nil},
+			&ResourceID{"some-gce-project", "projects", nil, meta.VersionGA, ""},
 		},
 		{
 			"projects/some-gce-project/regions/us-central1",
-			&ResourceID{"some-gce-project", "regions", meta.GlobalKey("us-central1")},
+			&ResourceID{"some-gce-project", "regions", meta.GlobalKey("us-central1"), meta.VersionGA, ""},
 		},
 		{
 			"projects/some-gce-project/zones/us-central1-b",
-			&ResourceID{"some-gce-project", "zones", meta.GlobalKey("us-central1-b")},
+			&ResourceID{"some-gce-project", "zones", meta.GlobalKey("us-central1-b"), meta.VersionGA, ""},
 		},
 		{
 			"projects/some-gce-project/global/operations/operation-1513289952196-56054460af5a0-b1dae0c3-9bbf9dbf",
-			&ResourceID{"some-gce-project", "operations", meta.GlobalKey("operation-1513289952196-56054460af5a0-b1dae0c3-9bbf9dbf")},
+			&ResourceID{"some-gce-project", "operations", meta.GlobalKey("operation-1513289952196-56054460af5a0-b1dae0c3-9bbf9dbf"), meta.VersionGA, ""},
 		},
 		{
 			"projects/some-gce-project/regions/us-central1/addresses/my-address",
-			&ResourceID{"some-gce-project", "addresses", meta.RegionalKey("my-address", "us-central1")},
+			&ResourceID{"some-gce-project", "addresses", meta.RegionalKey("my-address", "us-central1"), meta.VersionGA, ""},
 		},
 		{
 			"projects/some-gce-project/zones/us-central1-c/instances/instance-1",
-			&ResourceID{"some-gce-project", "instances", meta.ZonalKey("instance-1", "us-central1-c")},
+			&ResourceID{"some-gce-project", "instances", meta.ZonalKey("instance-1", "us-central1-c"), meta.VersionGA, ""},
 		},
 	} {
 		r, err := ParseResourceURL(tc.in)
@@ -112,6 +115,59 @@ func TestParseResourceURL(t *testing.T) {
 	}
 }
 
+func TestParseResourceURLRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, link := range []string{
+		"https://www.googleapis.com/compute/alpha/projects/some-gce-project/regions/us-central1/addresses/my-address",
+		"https://www.googleapis.com/compute/beta/projects/some-gce-project/zones/us-central1-c/instances/instance-1",
+	} {
+		r, err := ParseResourceURL(link)
+		if err != nil {
+			t.Errorf("ParseResourceURL(%q) = %v, want nil", link, err)
+			continue
+		}
+		if got := r.SelfLink(); got != link {
+			t.Errorf("ParseResourceURL(%q).SelfLink() = %q, want %q", link, got, link)
+		}
+	}
+}
+
+func TestResourceLinkerSharedVPC(t *testing.T) {
+	t.Parallel()
+
+	// host-project owns the shared-VPC subnetwork; service-project's
+	// instance references that subnetwork by self-link, as happens with
+	// shared-VPC, peered networks and cross-project service attachments.
+	subnetLink := "https://www.googleapis.com/compute/v1/projects/host-project/regions/us-central1/subnetworks/shared-vpc"
+	instanceLink := "https://www.googleapis.com/compute/v1/projects/service-project/zones/us-central1-c/instances/instance-1"
+
+	// A linker scoped to service-project can parse its own resources...
+	svc := &ResourceLinker{DefaultProject: "service-project"}
+	if _, err := svc.Parse(instanceLink); err != nil {
+		t.Errorf("svc.Parse(%q) = %v, want nil", instanceLink, err)
+	}
+	// ...but rejects a link into a different project.
+	if _, err := svc.Parse(subnetLink); err == nil {
+		t.Errorf("svc.Parse(%q) = nil, want error", subnetLink)
+	}
+
+	// An unscoped linker can parse the subnetwork link from the host project.
+	subnet, err := (&ResourceLinker{}).Parse(subnetLink)
+	if err != nil {
+		t.Fatalf("Parse(%q) = %v, want nil", subnetLink, err)
+	}
+	if subnet.ProjectID != "host-project" {
+		t.Errorf("subnet.ProjectID = %q, want %q", subnet.ProjectID, "host-project")
+	}
+
+	// svc can still build a link back into host-project with WithProject.
+	got := svc.SelfLink(meta.VersionGA, "subnetworks", *subnet.Key, WithProject("host-project"))
+	if got != subnetLink {
+		t.Errorf("svc.SelfLink(..., WithProject(%q)) = %q, want %q", "host-project", got, subnetLink)
+	}
+}
+
 type A struct {
 	A, B, C string
 }
@@ -120,12 +176,6 @@ type B struct {
 	A, B, D string
 }
 
-type E struct{}
-
-func (*E) MarshalJSON() ([]byte, error) {
-	return nil, errors.New("injected error")
-}
-
 func TestCopyVisJSON(t *testing.T) {
 	t.Parallel()
 
@@ -152,10 +202,6 @@ func TestCopyVisJSON(t *testing.T) {
 			t.Errorf("a == %+v, want %+v", a, expectedA)
 		}
 	}
-
-	if err := copyViaJSON(&a, &E{}); err == nil {
-		t.Errorf("copyViaJSON(&a, &E{}) = nil, want error")
-	}
 }
 
 func TestSelfLink(t *testing.T) {